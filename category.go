@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// Category groups objects stored under the same logical path, e.g. "csv"
+// reports vs "image" thumbnails, by inserting itself as a path segment:
+// env/[devUser/]path/<category>/<filename>. Custom categories beyond the
+// predefined ones are just strings.
+type Category string
+
+const (
+	CategoryCSV   Category = "csv"
+	CategoryImage Category = "image"
+)
+
+// Upload stores data under path/cat/filename on the given backend.
+func Upload(ctx context.Context, s Storage, cat Category, path, filename string, data []byte, contentType string) error {
+	return s.Upload(ctx, filepath.Join(path, string(cat)), filename, data, contentType)
+}
+
+// List returns the objects stored under path/cat on the given backend.
+func List(ctx context.Context, s Storage, cat Category, path string) ([]FileInfo, error) {
+	return s.List(ctx, filepath.Join(path, string(cat)))
+}
+
+// Remove deletes path/cat/filename from the given backend.
+func Remove(ctx context.Context, s Storage, cat Category, path, filename string) error {
+	return s.Remove(ctx, filepath.Join(path, string(cat)), filename)
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"os"
+)
+
+// GetStorageClient builds the default S3-backed Storage for the given
+// environment. The bucket is resolved by the s3 backend's own
+// S3_*/AWS_*/R2_* precedence (see s3.LoadS3ConfigFromEnv); the URI carries
+// no bucket host, so nothing here overrides that resolution. Callers must
+// import storage/s3 (even as a blank import) so the "s3" scheme is
+// registered.
+func GetStorageClient(env Environment) (Storage, error) {
+	devUser := os.Getenv("R2_USERNAME")
+	if devUser == "" {
+		devUser = "default-user"
+	}
+
+	return FromURI("s3://", env, devUser)
+}
@@ -0,0 +1,133 @@
+// Package local is a local-filesystem implementation of storage.Storage,
+// writing objects under a configurable root directory.
+package local
+
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	storage "github.com/nglushkov/tp-storage"
+)
+
+func init() {
+	storage.Register("file", func(u *url.URL, env storage.Environment, devUser string) (storage.Storage, error) {
+		root := u.Path
+		if root == "" {
+			root = u.Opaque
+		}
+		return NewClient(root, env, devUser), nil
+	})
+}
+
+// Client stores objects as regular files under root, mirroring the same
+// env/devUser/path layout the S3 backend uses for its object keys.
+type Client struct {
+	root        string
+	environment storage.Environment
+	devUser     string
+}
+
+// NewClient returns a Client rooted at the given directory. The directory is
+// created lazily on first write.
+func NewClient(root string, env storage.Environment, devUser string) *Client {
+	return &Client{
+		root:        root,
+		environment: env,
+		devUser:     devUser,
+	}
+}
+
+func (c *Client) buildPath(path string) string {
+	return storage.BuildPath(c.environment, c.devUser, path)
+}
+
+func (c *Client) fullPath(path, filename string) string {
+	return filepath.Join(c.root, c.buildPath(filepath.Join(path, filename)))
+}
+
+// Upload implements storage.Storage. contentType is ignored; the filesystem
+// has no notion of it.
+func (c *Client) Upload(ctx context.Context, path, filename string, data []byte, contentType string) error {
+	dest := c.fullPath(path, filename)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0o644)
+}
+
+// Download implements storage.Storage.
+func (c *Client) Download(ctx context.Context, path, filename string) ([]byte, error) {
+	return os.ReadFile(c.fullPath(path, filename))
+}
+
+// List implements storage.Storage, recursively walking path and returning
+// one FileInfo per file, keyed the same way the S3 backend keys its objects.
+func (c *Client) List(ctx context.Context, path string) ([]storage.FileInfo, error) {
+	base := filepath.Join(c.root, c.buildPath(path))
+
+	var files []storage.FileInfo
+	err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		key, err := filepath.Rel(c.root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, storage.FileInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Remove implements storage.Storage.
+func (c *Client) Remove(ctx context.Context, path, filename string) error {
+	return os.Remove(c.fullPath(path, filename))
+}
+
+// Exists implements storage.Storage.
+func (c *Client) Exists(ctx context.Context, path, filename string) (bool, error) {
+	_, err := os.Stat(c.fullPath(path, filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TestConnection implements storage.Storage, verifying that root exists (or
+// can be created) and is writable.
+func (c *Client) TestConnection(ctx context.Context) error {
+	if err := os.MkdirAll(c.root, 0o755); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(c.root, ".tp-storage-probe-*")
+	if err != nil {
+		return err
+	}
+	name := probe.Name()
+	probe.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,85 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	storage "github.com/nglushkov/tp-storage"
+)
+
+func TestClientUploadDownloadListRemoveExists(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(t.TempDir(), storage.Development, "alice")
+
+	ok, err := c.Exists(ctx, "reports", "a.csv")
+	if err != nil {
+		t.Fatalf("Exists() before Upload = %v, want nil error", err)
+	}
+	if ok {
+		t.Fatal("Exists() before Upload = true, want false")
+	}
+
+	if err := c.Upload(ctx, "reports", "a.csv", []byte("id,name\n1,x"), "text/csv"); err != nil {
+		t.Fatalf("Upload() = %v, want nil error", err)
+	}
+
+	ok, err = c.Exists(ctx, "reports", "a.csv")
+	if err != nil {
+		t.Fatalf("Exists() after Upload = %v, want nil error", err)
+	}
+	if !ok {
+		t.Fatal("Exists() after Upload = false, want true")
+	}
+
+	data, err := c.Download(ctx, "reports", "a.csv")
+	if err != nil {
+		t.Fatalf("Download() = %v, want nil error", err)
+	}
+	if string(data) != "id,name\n1,x" {
+		t.Fatalf("Download() = %q, want %q", data, "id,name\n1,x")
+	}
+
+	files, err := c.List(ctx, "reports")
+	if err != nil {
+		t.Fatalf("List() = %v, want nil error", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("List() returned %d files, want 1: %+v", len(files), files)
+	}
+
+	if err := c.Remove(ctx, "reports", "a.csv"); err != nil {
+		t.Fatalf("Remove() = %v, want nil error", err)
+	}
+
+	ok, err = c.Exists(ctx, "reports", "a.csv")
+	if err != nil {
+		t.Fatalf("Exists() after Remove = %v, want nil error", err)
+	}
+	if ok {
+		t.Fatal("Exists() after Remove = true, want false")
+	}
+}
+
+func TestClientListNonExistentPath(t *testing.T) {
+	c := NewClient(t.TempDir(), storage.Development, "")
+
+	files, err := c.List(context.Background(), "never-uploaded")
+	if err != nil {
+		t.Fatalf("List() on a path with no objects = %v, want nil error", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("List() on a path with no objects returned %d files, want 0", len(files))
+	}
+}
+
+func TestClientTestConnectionCreatesRoot(t *testing.T) {
+	root := t.TempDir() + "/nested/does/not/exist/yet"
+	c := NewClient(root, storage.Development, "")
+
+	if err := c.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection() = %v, want nil error", err)
+	}
+	if _, err := NewClient(root, storage.Development, "").Exists(context.Background(), "x", "y"); err != nil {
+		t.Fatalf("Exists() on freshly created root = %v, want nil error", err)
+	}
+}
@@ -0,0 +1,115 @@
+// Package memory is an in-process, map-backed implementation of
+// storage.Storage intended for unit tests that don't want to depend on
+// LocalStack or a real filesystem.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	storage "github.com/nglushkov/tp-storage"
+)
+
+func init() {
+	storage.Register("mem", func(u *url.URL, env storage.Environment, devUser string) (storage.Storage, error) {
+		return NewClient(env, devUser), nil
+	})
+}
+
+type object struct {
+	data []byte
+	mod  time.Time
+}
+
+// Client keeps all objects in an in-process map. It is safe for concurrent
+// use and is never persisted to disk.
+type Client struct {
+	mu          sync.RWMutex
+	objects     map[string]object
+	environment storage.Environment
+	devUser     string
+}
+
+// NewClient returns an empty Client.
+func NewClient(env storage.Environment, devUser string) *Client {
+	return &Client{
+		objects:     make(map[string]object),
+		environment: env,
+		devUser:     devUser,
+	}
+}
+
+func (c *Client) buildPath(path string) string {
+	return storage.BuildPath(c.environment, c.devUser, path)
+}
+
+func (c *Client) key(path, filename string) string {
+	return filepath.ToSlash(c.buildPath(filepath.Join(path, filename)))
+}
+
+// Upload implements storage.Storage. contentType is ignored.
+func (c *Client) Upload(ctx context.Context, path, filename string, data []byte, contentType string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cp := make([]byte, len(data))
+	copy(cp, data)
+	c.objects[c.key(path, filename)] = object{data: cp, mod: time.Now()}
+	return nil
+}
+
+// Download implements storage.Storage.
+func (c *Client) Download(ctx context.Context, path, filename string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	obj, ok := c.objects[c.key(path, filename)]
+	if !ok {
+		return nil, fmt.Errorf("storage/memory: object %q not found", c.key(path, filename))
+	}
+	return obj.data, nil
+}
+
+// List implements storage.Storage.
+func (c *Client) List(ctx context.Context, path string) ([]storage.FileInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	prefix := filepath.ToSlash(c.buildPath(path))
+
+	files := make([]storage.FileInfo, 0)
+	for key, obj := range c.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		files = append(files, storage.FileInfo{
+			Key:          key,
+			Size:         int64(len(obj.data)),
+			LastModified: obj.mod,
+		})
+	}
+	return files, nil
+}
+
+// Remove implements storage.Storage.
+func (c *Client) Remove(ctx context.Context, path, filename string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.objects, c.key(path, filename))
+	return nil
+}
+
+// Exists implements storage.Storage.
+func (c *Client) Exists(ctx context.Context, path, filename string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.objects[c.key(path, filename)]
+	return ok, nil
+}
+
+// TestConnection implements storage.Storage and always succeeds.
+func (c *Client) TestConnection(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Factory builds a Storage backend from a parsed connection URI. Backends
+// register themselves from an init() in their own package (see storage/s3,
+// storage/local, storage/memory) to avoid this package importing them
+// directly, so callers opt into a backend simply by importing it.
+type Factory func(uri *url.URL, env Environment, devUser string) (Storage, error)
+
+var registry = map[string]Factory{}
+
+// Register makes a Storage backend available under the given URI scheme.
+// It is meant to be called from an init() function; it panics on duplicate
+// registration of the same scheme.
+func Register(scheme string, factory Factory) {
+	if _, exists := registry[scheme]; exists {
+		panic(fmt.Sprintf("storage: backend already registered for scheme %q", scheme))
+	}
+	registry[scheme] = factory
+}
+
+// FromURI picks a Storage backend by connection-string scheme, e.g.
+// "s3://bucket?region=us-east-1", "file:///var/data", or "mem://". The
+// backend package for the requested scheme must have been imported (for its
+// init-time Register call) or FromURI returns an error.
+func FromURI(uri string, env Environment, devUser string) (Storage, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid URI %q: %w", uri, err)
+	}
+
+	factory, ok := registry[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("storage: no backend registered for scheme %q (forgot to import it?)", u.Scheme)
+	}
+	return factory(u, env, devUser)
+}
@@ -0,0 +1,58 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	storage "github.com/nglushkov/tp-storage"
+	_ "github.com/nglushkov/tp-storage/memory"
+)
+
+// TestFromURIRoundTripsThroughMemory exercises the registry (Register/
+// FromURI) and the category helpers (Upload/List/Remove) together against
+// storage/memory, the backend chunk0-1 added specifically so this kind of
+// test wouldn't need LocalStack.
+func TestFromURIRoundTripsThroughMemory(t *testing.T) {
+	s, err := storage.FromURI("mem://", storage.Development, "alice")
+	if err != nil {
+		t.Fatalf("FromURI(mem://) = %v, want nil error", err)
+	}
+
+	ctx := context.Background()
+	if err := storage.Upload(ctx, s, storage.CategoryCSV, "reports", "a.csv", []byte("id,name\n1,x"), "text/csv"); err != nil {
+		t.Fatalf("Upload() = %v, want nil error", err)
+	}
+
+	files, err := storage.List(ctx, s, storage.CategoryCSV, "reports")
+	if err != nil {
+		t.Fatalf("List() = %v, want nil error", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("List() returned %d files, want 1: %+v", len(files), files)
+	}
+
+	other, err := storage.List(ctx, s, storage.CategoryImage, "reports")
+	if err != nil {
+		t.Fatalf("List(CategoryImage) = %v, want nil error", err)
+	}
+	if len(other) != 0 {
+		t.Fatalf("List(CategoryImage) returned %d files, want 0 (csv/image categories must not collide)", len(other))
+	}
+
+	if err := storage.Remove(ctx, s, storage.CategoryCSV, "reports", "a.csv"); err != nil {
+		t.Fatalf("Remove() = %v, want nil error", err)
+	}
+	files, err = storage.List(ctx, s, storage.CategoryCSV, "reports")
+	if err != nil {
+		t.Fatalf("List() after Remove = %v, want nil error", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("List() after Remove returned %d files, want 0", len(files))
+	}
+}
+
+func TestFromURIUnknownScheme(t *testing.T) {
+	if _, err := storage.FromURI("gs://bucket", storage.Development, ""); err == nil {
+		t.Fatal("FromURI(gs://...) = nil error, want error for unregistered scheme")
+	}
+}
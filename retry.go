@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures the exponential backoff used by
+// TestConnectionWithRetry.
+type RetryPolicy struct {
+	// InitialDelay is the wait before the second attempt. Defaults to 1s
+	// if zero.
+	InitialDelay time.Duration
+	// MaxDelay caps the backoff delay. Zero means uncapped.
+	MaxDelay time.Duration
+	// MaxAttempts is the total number of tries, including the first.
+	// Defaults to 1 (no retry) if zero.
+	MaxAttempts int
+	// Jitter is the fraction (0-1) of each delay to randomize, to avoid
+	// a thundering herd of clients retrying in lockstep.
+	Jitter float64
+}
+
+// TestConnectionWithRetry calls s.TestConnection repeatedly with
+// exponential backoff until it succeeds or policy.MaxAttempts is
+// exhausted. Useful during service startup to wait for LocalStack/MinIO
+// to become reachable.
+func TestConnectionWithRetry(ctx context.Context, s Storage, policy RetryPolicy) error {
+	delay := policy.InitialDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = s.TestConnection(ctx)
+		if lastErr == nil {
+			return nil
+		}
+		if attempt == maxAttempts {
+			break
+		}
+
+		wait := delay
+		if policy.Jitter > 0 {
+			wait += time.Duration(policy.Jitter * rand.Float64() * float64(wait))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+	return fmt.Errorf("storage: connection test failed after %d attempts: %w", maxAttempts, lastErr)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeConn is a minimal Storage that only cares about TestConnection, for
+// exercising TestConnectionWithRetry without a real backend.
+type fakeConn struct {
+	failures int // number of calls that return errConnNotReady before succeeding
+	calls    int
+}
+
+var errConnNotReady = errors.New("storage: not ready")
+
+func (f *fakeConn) TestConnection(ctx context.Context) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errConnNotReady
+	}
+	return nil
+}
+
+func (f *fakeConn) Upload(ctx context.Context, path, filename string, data []byte, contentType string) error {
+	return nil
+}
+func (f *fakeConn) Download(ctx context.Context, path, filename string) ([]byte, error) {
+	return nil, nil
+}
+func (f *fakeConn) List(ctx context.Context, path string) ([]FileInfo, error) { return nil, nil }
+func (f *fakeConn) Remove(ctx context.Context, path, filename string) error   { return nil }
+func (f *fakeConn) Exists(ctx context.Context, path, filename string) (bool, error) {
+	return false, nil
+}
+
+func TestTestConnectionWithRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int
+		maxAttempts int
+		wantCalls   int
+		wantErr     bool
+	}{
+		{name: "succeeds first try", failures: 0, maxAttempts: 3, wantCalls: 1, wantErr: false},
+		{name: "succeeds after retries", failures: 2, maxAttempts: 3, wantCalls: 3, wantErr: false},
+		{name: "exhausts attempts", failures: 5, maxAttempts: 3, wantCalls: 3, wantErr: true},
+		{name: "zero MaxAttempts defaults to one try", failures: 1, maxAttempts: 0, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{failures: tt.failures}
+			err := TestConnectionWithRetry(context.Background(), conn, RetryPolicy{
+				InitialDelay: time.Millisecond,
+				MaxDelay:     5 * time.Millisecond,
+				MaxAttempts:  tt.maxAttempts,
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TestConnectionWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if conn.calls != tt.wantCalls {
+				t.Fatalf("TestConnection called %d times, want %d", conn.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTestConnectionWithRetryContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conn := &fakeConn{failures: 1}
+	err := TestConnectionWithRetry(ctx, conn, RetryPolicy{
+		InitialDelay: time.Millisecond,
+		MaxAttempts:  3,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("TestConnectionWithRetry() error = %v, want context.Canceled", err)
+	}
+}
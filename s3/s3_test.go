@@ -0,0 +1,158 @@
+package s3
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadS3ConfigFromEnvPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want S3Config
+	}{
+		{
+			name: "defaults when nothing set",
+			env:  map[string]string{},
+			want: S3Config{BucketName: "default", Region: "auto"},
+		},
+		{
+			name: "R2_* used when nothing else set",
+			env: map[string]string{
+				"R2_ACCESS_KEY_ID":     "r2-key",
+				"R2_SECRET_ACCESS_KEY": "r2-secret",
+				"R2_BUCKET_NAME":       "r2-bucket",
+				"R2_REGION":            "auto",
+				"R2_ENDPOINT":          "https://r2.example.com",
+			},
+			want: S3Config{
+				AccessKeyID:     "r2-key",
+				SecretAccessKey: "r2-secret",
+				BucketName:      "r2-bucket",
+				Region:          "auto",
+				Endpoint:        "https://r2.example.com",
+			},
+		},
+		{
+			name: "AWS_* overrides R2_*",
+			env: map[string]string{
+				"R2_BUCKET_NAME":    "r2-bucket",
+				"AWS_BUCKET_NAME":   "aws-bucket",
+				"R2_REGION":         "auto",
+				"AWS_REGION":        "us-west-2",
+				"R2_ACCESS_KEY_ID":  "r2-key",
+				"AWS_ACCESS_KEY_ID": "aws-key",
+			},
+			want: S3Config{
+				AccessKeyID: "aws-key",
+				BucketName:  "aws-bucket",
+				Region:      "us-west-2",
+			},
+		},
+		{
+			name: "S3_* overrides AWS_* and R2_*",
+			env: map[string]string{
+				"R2_BUCKET_NAME":  "r2-bucket",
+				"AWS_BUCKET_NAME": "aws-bucket",
+				"S3_BUCKET_NAME":  "s3-bucket",
+				"AWS_REGION":      "us-west-2",
+				"S3_REGION":       "eu-central-1",
+			},
+			want: S3Config{
+				BucketName: "s3-bucket",
+				Region:     "eu-central-1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"S3_ACCESS_KEY_ID", "AWS_ACCESS_KEY_ID", "R2_ACCESS_KEY_ID",
+				"S3_SECRET_ACCESS_KEY", "AWS_SECRET_ACCESS_KEY", "R2_SECRET_ACCESS_KEY",
+				"S3_BUCKET_NAME", "AWS_BUCKET_NAME", "R2_BUCKET_NAME",
+				"S3_REGION", "AWS_REGION", "R2_REGION",
+				"S3_ENDPOINT", "AWS_ENDPOINT_URL", "R2_ENDPOINT",
+				"S3_CREDENTIALS_MODE", "AWS_CREDENTIALS_MODE", "AWS_PROFILE",
+			} {
+				t.Setenv(key, "")
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			got := LoadS3ConfigFromEnv()
+			if got.AccessKeyID != tt.want.AccessKeyID {
+				t.Errorf("AccessKeyID = %q, want %q", got.AccessKeyID, tt.want.AccessKeyID)
+			}
+			if got.SecretAccessKey != tt.want.SecretAccessKey {
+				t.Errorf("SecretAccessKey = %q, want %q", got.SecretAccessKey, tt.want.SecretAccessKey)
+			}
+			if got.BucketName != tt.want.BucketName {
+				t.Errorf("BucketName = %q, want %q", got.BucketName, tt.want.BucketName)
+			}
+			if got.Region != tt.want.Region {
+				t.Errorf("Region = %q, want %q", got.Region, tt.want.Region)
+			}
+			if got.Endpoint != tt.want.Endpoint {
+				t.Errorf("Endpoint = %q, want %q", got.Endpoint, tt.want.Endpoint)
+			}
+		})
+	}
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := NewClient(S3Config{
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+		BucketName:      "test-bucket",
+		Region:          "us-east-1",
+		Endpoint:        "https://s3.example.com",
+	}, "dev", "alice")
+	if err != nil {
+		t.Fatalf("NewClient() = %v, want nil error", err)
+	}
+	return c
+}
+
+func TestPresignDownload(t *testing.T) {
+	c := newTestClient(t)
+
+	rawURL, err := c.PresignDownload(context.Background(), "reports", "a.csv", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("PresignDownload() = %v, want nil error", err)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("PresignDownload() returned unparseable URL %q: %v", rawURL, err)
+	}
+	if !strings.Contains(u.Path, "test-bucket") {
+		t.Errorf("PresignDownload() URL %q does not reference bucket %q", rawURL, "test-bucket")
+	}
+	if !strings.HasSuffix(u.Path, "a.csv") {
+		t.Errorf("PresignDownload() URL %q does not end with the object key", rawURL)
+	}
+	if u.Query().Get("X-Amz-Expires") != "900" {
+		t.Errorf("PresignDownload() X-Amz-Expires = %q, want %q", u.Query().Get("X-Amz-Expires"), "900")
+	}
+}
+
+func TestPresignUpload(t *testing.T) {
+	c := newTestClient(t)
+
+	rawURL, headers, err := c.PresignUpload(context.Background(), "images", "a.png", "image/png", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignUpload() = %v, want nil error", err)
+	}
+	if rawURL == "" {
+		t.Fatal("PresignUpload() returned an empty URL")
+	}
+	if got := headers["Content-Type"]; got != "image/png" {
+		t.Errorf("PresignUpload() headers[Content-Type] = %q, want %q", got, "image/png")
+	}
+}